@@ -0,0 +1,240 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// OverflowPolicy controls what happens when the async buffer is full and a new record arrives.
+type OverflowPolicy int
+
+const (
+	// DropNewest discards the record that doesn't fit, keeping everything already queued.
+	DropNewest OverflowPolicy = iota
+	// DropOldest discards the oldest queued record to make room for the new one.
+	DropOldest
+	// Block waits for room in the queue, applying backpressure to the caller.
+	Block
+)
+
+// asyncRecord is either a dispatchable log record, or a marker used internally by flush to know
+// when every record queued ahead of it has drained.
+type asyncRecord struct {
+	level   Level
+	produce func() string
+	marker  func()
+}
+
+type asyncState struct {
+	queue    chan asyncRecord
+	overflow OverflowPolicy
+	done     chan struct{}
+	wg       sync.WaitGroup
+
+	mu      sync.Mutex
+	dropped uint64
+	onDrop  func(count uint64)
+	onFlush func(duration time.Duration)
+}
+
+var (
+	asyncMu sync.RWMutex
+	async   *asyncState
+)
+
+// EnableAsync starts a background goroutine that drains dispatched records into the sink
+// registry, decoupling callers from sink latency. bufferSize bounds how many records can be
+// queued before overflow applies. Calling EnableAsync again replaces the running pipeline after
+// draining whatever it had queued.
+func EnableAsync(bufferSize int, overflow OverflowPolicy) {
+	asyncMu.Lock()
+	defer asyncMu.Unlock()
+
+	if async != nil {
+		close(async.done)
+		async.wg.Wait()
+	}
+
+	a := &asyncState{
+		queue:    make(chan asyncRecord, bufferSize),
+		overflow: overflow,
+		done:     make(chan struct{}),
+	}
+	a.wg.Add(1)
+	go a.run()
+	async = a
+}
+
+// DisableAsync drains whatever the async pipeline had queued, stops its background goroutine,
+// and restores synchronous dispatch. It's a no-op if async logging isn't enabled.
+func DisableAsync() {
+	asyncMu.Lock()
+	a := async
+	async = nil
+	asyncMu.Unlock()
+
+	if a == nil {
+		return
+	}
+	close(a.done)
+	a.wg.Wait()
+}
+
+func (a *asyncState) run() {
+	defer a.wg.Done()
+	for {
+		select {
+		case rec := <-a.queue:
+			a.handle(rec)
+		case <-a.done:
+			a.drain()
+			return
+		}
+	}
+}
+
+// drain dispatches every record still sitting in the queue without blocking for more.
+func (a *asyncState) drain() {
+	for {
+		select {
+		case rec := <-a.queue:
+			a.handle(rec)
+		default:
+			return
+		}
+	}
+}
+
+func (a *asyncState) handle(rec asyncRecord) {
+	if rec.marker != nil {
+		rec.marker()
+		return
+	}
+	dispatch(rec.level, rec.produce)
+}
+
+// OnDrop registers a callback invoked every time the active async pipeline drops one or more
+// records due to overflow, with the running total dropped since the pipeline was enabled.
+func OnDrop(f func(count uint64)) {
+	asyncMu.RLock()
+	defer asyncMu.RUnlock()
+	if async != nil {
+		async.mu.Lock()
+		async.onDrop = f
+		async.mu.Unlock()
+	}
+}
+
+// OnFlush registers a callback invoked every time Flush or Shutdown finishes draining the queue,
+// with how long that took. It isn't invoked when ctx is done before the queue has drained.
+func OnFlush(f func(duration time.Duration)) {
+	asyncMu.RLock()
+	defer asyncMu.RUnlock()
+	if async != nil {
+		async.mu.Lock()
+		async.onFlush = f
+		async.mu.Unlock()
+	}
+}
+
+// submit routes a dispatched record either straight to the sink registry (the default,
+// synchronous behavior) or onto the async queue if EnableAsync has been called.
+func submit(level Level, produce func() string) {
+	asyncMu.RLock()
+	a := async
+	asyncMu.RUnlock()
+
+	if a == nil {
+		dispatch(level, produce)
+		return
+	}
+	a.enqueue(level, produce)
+}
+
+func (a *asyncState) enqueue(level Level, produce func() string) {
+	rec := asyncRecord{level: level, produce: produce}
+	switch a.overflow {
+	case Block:
+		a.queue <- rec
+	case DropOldest:
+		select {
+		case a.queue <- rec:
+		default:
+			select {
+			case <-a.queue:
+				a.recordDrop()
+			default:
+			}
+			select {
+			case a.queue <- rec:
+			default:
+				a.recordDrop()
+			}
+		}
+	default: // DropNewest
+		select {
+		case a.queue <- rec:
+		default:
+			a.recordDrop()
+		}
+	}
+}
+
+func (a *asyncState) recordDrop() {
+	a.mu.Lock()
+	a.dropped++
+	count := a.dropped
+	cb := a.onDrop
+	a.mu.Unlock()
+	if cb != nil {
+		cb(count)
+	}
+}
+
+// Flush blocks until every record queued so far has been dispatched to the sink registry, or
+// ctx is done. Flush is a no-op if async logging isn't enabled, since every call is already
+// synchronous in that mode.
+func Flush(ctx context.Context) error {
+	asyncMu.RLock()
+	a := async
+	asyncMu.RUnlock()
+	if a == nil {
+		return nil
+	}
+	return a.flush(ctx)
+}
+
+func (a *asyncState) flush(ctx context.Context) error {
+	start := time.Now()
+	marker := make(chan struct{})
+
+	select {
+	case a.queue <- asyncRecord{marker: func() { close(marker) }}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-marker:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	a.mu.Lock()
+	cb := a.onFlush
+	a.mu.Unlock()
+	if cb != nil {
+		cb(time.Since(start))
+	}
+	return nil
+}
+
+// Shutdown flushes every outstanding record and stops the async pipeline, restoring synchronous
+// dispatch. It's safe to call even if async logging was never enabled.
+func Shutdown(ctx context.Context) error {
+	if err := Flush(ctx); err != nil {
+		return err
+	}
+	DisableAsync()
+	return nil
+}
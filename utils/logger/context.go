@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+)
+
+// fieldsContextKey is the single context.Context key under which this package stores every
+// well-known field, so serializeContext only has to do one lookup instead of one per field.
+type fieldsContextKey struct{}
+
+// WithFields returns a copy of ctx carrying fields in addition to any already attached to ctx.
+// A field whose key matches one inherited from the parent context overrides it in the emitted
+// log record.
+func WithFields(ctx context.Context, fields ...Field) context.Context {
+	existing, _ := ctx.Value(fieldsContextKey{}).([]Field)
+	merged := make([]Field, len(existing), len(existing)+len(fields))
+	copy(merged, existing)
+	merged = append(merged, fields...)
+	return context.WithValue(ctx, fieldsContextKey{}, merged)
+}
+
+// AppendField returns a copy of ctx carrying a single additional key/value field.
+func AppendField(ctx context.Context, key string, value interface{}) context.Context {
+	return WithFields(ctx, Field{Key: key, Value: value})
+}
+
+// WithUserID returns a copy of ctx carrying userID for inclusion in subsequent log output.
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return WithFields(ctx, Field{Key: "user-id", Value: userID})
+}
+
+// WithRequestID returns a copy of ctx carrying requestID for inclusion in subsequent log output.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return WithFields(ctx, Field{Key: "request-id", Value: requestID})
+}
+
+// WithSessionID returns a copy of ctx carrying sessionID for inclusion in subsequent log output.
+func WithSessionID(ctx context.Context, sessionID string) context.Context {
+	return WithFields(ctx, Field{Key: "session-id", Value: sessionID})
+}
+
+// WithTeamID returns a copy of ctx carrying teamID for inclusion in subsequent log output.
+func WithTeamID(ctx context.Context, teamID string) context.Context {
+	return WithFields(ctx, Field{Key: "team-id", Value: teamID})
+}
+
+// WithChannelID returns a copy of ctx carrying channelID for inclusion in subsequent log output.
+func WithChannelID(ctx context.Context, channelID string) context.Context {
+	return WithFields(ctx, Field{Key: "channel-id", Value: channelID})
+}
+
+// WithIPAddress returns a copy of ctx carrying ipAddress for inclusion in subsequent log output.
+func WithIPAddress(ctx context.Context, ipAddress string) context.Context {
+	return WithFields(ctx, Field{Key: "ip-address", Value: ipAddress})
+}
+
+// WithTraceID returns a copy of ctx carrying traceID, allowing correlation with a distributed
+// tracing system.
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return WithFields(ctx, Field{Key: "trace-id", Value: traceID})
+}
+
+// WithSpanID returns a copy of ctx carrying spanID, allowing correlation with a distributed
+// tracing system.
+func WithSpanID(ctx context.Context, spanID string) context.Context {
+	return WithFields(ctx, Field{Key: "span-id", Value: spanID})
+}
+
+// serializeContext flattens the fields attached to ctx into a map suitable for embedding in a
+// JSON log record. Fields are walked in attachment order, so a later duplicate key (e.g. one
+// added by a child context) overrides an earlier one.
+func serializeContext(ctx context.Context) map[string]string {
+	fields, _ := ctx.Value(fieldsContextKey{}).([]Field)
+	serialized := make(map[string]string, len(fields))
+	for _, field := range fields {
+		serialized[field.Key] = fmt.Sprint(field.Value)
+	}
+	return serialized
+}
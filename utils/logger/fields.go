@@ -0,0 +1,28 @@
+package logger
+
+// Field is a single structured key/value pair attached to a log record. Typed constructors like
+// String and Int return a Field directly, which the Logger methods take as-is rather than pairing
+// it with the argument that follows. Value is an interface{}, so a typed constructor still boxes
+// its value, and a Field passed as a keysAndValues argument is itself boxed.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// String builds a Field carrying a string value.
+func String(key, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int builds a Field carrying an int value.
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err builds a Field carrying the message of err under the conventional "error" key.
+func Err(err error) Field {
+	if err == nil {
+		return Field{Key: "error", Value: nil}
+	}
+	return Field{Key: "error", Value: err.Error()}
+}
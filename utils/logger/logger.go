@@ -0,0 +1,236 @@
+// this is a new logger interface for mattermost
+
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+)
+
+// debug, info and err are indirections around the underlying log output. They're package-level
+// vars (rather than being called directly) so that tests can substitute their own implementation
+// without requiring a real logging backend. Callers always pass a closure rather than a formatted
+// string so that the message isn't built unless a registered sink actually records it. err is the
+// exception: once the sampler lets a record through it builds the message up front, because it
+// also returns that message as an error.
+var debug = func(format interface{}, args ...interface{}) {
+	if !sample(LevelDebug, callerKey()) {
+		return
+	}
+	submit(LevelDebug, func() string { return resolve(format, args...) })
+}
+
+var info = func(format interface{}, args ...interface{}) {
+	if !sample(LevelInfo, callerKey()) {
+		return
+	}
+	submit(LevelInfo, func() string { return resolve(format, args...) })
+}
+
+var err = func(format interface{}, args ...interface{}) error {
+	if !sample(LevelError, callerKey()) {
+		return errDropped
+	}
+	message := resolve(format, args...)
+	submit(LevelError, func() string { return message })
+	return errors.New(message)
+}
+
+// resolve turns the format/args pair passed to debug/info/err into a single string, invoking
+// format if it's a closure or treating it as a Printf-style format string otherwise.
+func resolve(format interface{}, args ...interface{}) string {
+	if f, ok := format.(func() string); ok {
+		return f()
+	}
+	if s, ok := format.(string); ok {
+		return fmt.Sprintf(s, args...)
+	}
+	return fmt.Sprint(format)
+}
+
+// thisFile is resolved once at init time so caller() can tell its own frames apart from the
+// frames of whoever is calling into this package.
+var _, thisFile, _, _ = runtime.Caller(0)
+
+// thisDir is used by callerKey(), which walks past every file in this package's directory (not
+// just this one).
+var thisDir = filepath.Dir(thisFile)
+
+// caller walks up the stack past every frame that belongs to this exact file and returns the
+// first frame that doesn't, i.e. wherever the public logging API was actually invoked from. It's
+// deliberately scoped to thisFile rather than the whole package directory: callers that live in
+// another file of this same package (as logger_test.go does) must still be reported, not skipped
+// past.
+func caller() string {
+	for skip := 1; ; skip++ {
+		_, file, _, ok := runtime.Caller(skip)
+		if !ok {
+			return ""
+		}
+		if file != thisFile {
+			return file
+		}
+	}
+}
+
+// callerKey returns "file:line" for the call site the public logging API was invoked from, used
+// to group samples by call site. It's called from debug/info/err, before the record is dispatched.
+// Unlike caller(), it walks past every frame belonging to this package's directory, _test.go
+// files included.
+func callerKey() string {
+	for skip := 1; ; skip++ {
+		_, file, line, ok := runtime.Caller(skip)
+		if !ok {
+			return ""
+		}
+		if filepath.Dir(file) != thisDir {
+			return fmt.Sprintf("%s:%d", file, line)
+		}
+	}
+}
+
+// serializeLogMessage renders msg, the fields attached to ctx and any additional structured
+// fields as a single JSON log record, tagging it with the location of the caller.
+func serializeLogMessage(ctx context.Context, msg string, fields ...Field) string {
+	payload := map[string]interface{}{
+		"Context": serializeContext(ctx),
+		"Logger":  caller(),
+		"Message": msg,
+	}
+	for _, f := range fields {
+		payload[f.Key] = f.Value
+	}
+	serialized, _ := json.Marshal(payload)
+	return string(serialized)
+}
+
+// Debugc logs msg at debug level, attaching any fields recorded on ctx.
+func Debugc(ctx context.Context, msg string) {
+	debug(func() string { return serializeLogMessage(ctx, msg) })
+}
+
+// Debugf logs a Printf-formatted message at debug level with an empty context.
+func Debugf(format string, args ...interface{}) {
+	Debugc(context.Background(), fmt.Sprintf(format, args...))
+}
+
+// Infoc logs msg at info level, attaching any fields recorded on ctx.
+func Infoc(ctx context.Context, msg string) {
+	info(func() string { return serializeLogMessage(ctx, msg) })
+}
+
+// Infof logs a Printf-formatted message at info level with an empty context.
+func Infof(format string, args ...interface{}) {
+	Infoc(context.Background(), fmt.Sprintf(format, args...))
+}
+
+// Errorc logs msg at error level, attaching any fields recorded on ctx.
+func Errorc(ctx context.Context, msg string) {
+	err(func() string { return serializeLogMessage(ctx, msg) })
+}
+
+// Errorf logs a Printf-formatted message at error level with an empty context.
+func Errorf(format string, args ...interface{}) {
+	Errorc(context.Background(), fmt.Sprintf(format, args...))
+}
+
+// Logger is a structured, key/value logging API in the style of logr/klog. Callers typically
+// obtain one via With() so that a fixed set of fields (e.g. a request ID) is attached to every
+// subsequent call, and inject a mock implementation in tests the same way the package already
+// swaps out debug/info/err.
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+	Debugc(ctx context.Context, msg string, keysAndValues ...interface{})
+	Info(msg string, keysAndValues ...interface{})
+	Infoc(ctx context.Context, msg string, keysAndValues ...interface{})
+	Error(cause error, msg string, keysAndValues ...interface{})
+	Errorc(ctx context.Context, cause error, msg string, keysAndValues ...interface{})
+	With(keysAndValues ...interface{}) Logger
+}
+
+// defaultLogger is the Logger implementation backed by the package-level debug/info/err sinks.
+type defaultLogger struct {
+	fields []Field
+}
+
+// NewLogger returns a Logger with no fields attached.
+func NewLogger() Logger {
+	return &defaultLogger{}
+}
+
+// std is the Logger returned by the package-level With, so callers that only need a single
+// accumulation of fields don't have to construct their own root Logger.
+var std = NewLogger()
+
+// With returns a Logger that records keysAndValues on every subsequent call.
+func With(keysAndValues ...interface{}) Logger {
+	return std.With(keysAndValues...)
+}
+
+func (l *defaultLogger) With(keysAndValues ...interface{}) Logger {
+	return &defaultLogger{fields: mergeFields(l.fields, keysAndValues)}
+}
+
+func (l *defaultLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.Debugc(context.Background(), msg, keysAndValues...)
+}
+
+func (l *defaultLogger) Debugc(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	fields := mergeFields(l.fields, keysAndValues)
+	debug(func() string { return serializeLogMessage(ctx, msg, fields...) })
+}
+
+func (l *defaultLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.Infoc(context.Background(), msg, keysAndValues...)
+}
+
+func (l *defaultLogger) Infoc(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	fields := mergeFields(l.fields, keysAndValues)
+	info(func() string { return serializeLogMessage(ctx, msg, fields...) })
+}
+
+func (l *defaultLogger) Error(cause error, msg string, keysAndValues ...interface{}) {
+	l.Errorc(context.Background(), cause, msg, keysAndValues...)
+}
+
+func (l *defaultLogger) Errorc(ctx context.Context, cause error, msg string, keysAndValues ...interface{}) {
+	fields := mergeFields(l.fields, keysAndValues)
+	if cause != nil {
+		fields = append(fields, Err(cause))
+	}
+	err(func() string { return serializeLogMessage(ctx, msg, fields...) })
+}
+
+// mergeFields appends the Fields produced from keysAndValues onto a copy of base, leaving base
+// itself untouched so sibling Loggers created via With don't share a backing array.
+func mergeFields(base []Field, keysAndValues []interface{}) []Field {
+	merged := make([]Field, 0, len(base)+len(keysAndValues))
+	merged = append(merged, base...)
+	return append(merged, toFields(keysAndValues)...)
+}
+
+// toFields turns a logr-style keysAndValues list into Fields. Items that are already a Field
+// (e.g. produced by String/Int/Err) are taken as-is; everything else is treated as a key paired
+// with the following value. A trailing key with no value is paired with a MISSING sentinel
+// rather than being dropped or panicking.
+func toFields(keysAndValues []interface{}) []Field {
+	fields := make([]Field, 0, len(keysAndValues))
+	for i := 0; i < len(keysAndValues); i++ {
+		if f, ok := keysAndValues[i].(Field); ok {
+			fields = append(fields, f)
+			continue
+		}
+		key := fmt.Sprint(keysAndValues[i])
+		if i+1 < len(keysAndValues) {
+			i++
+			fields = append(fields, Field{Key: key, Value: keysAndValues[i]})
+		} else {
+			fields = append(fields, Field{Key: key, Value: "MISSING"})
+		}
+	}
+	return fields
+}
@@ -8,7 +8,10 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 // ensures that values can be recorded on a Context object, and that the data in question is serialized as a part of the log message
@@ -380,3 +383,372 @@ func TestErrorf(t *testing.T) {
 		}
 	})
 }
+
+// ensures that a structured Logger serializes key/value pairs as top-level JSON attributes
+func TestLoggerInfoFields(t *testing.T) {
+	t.Run("Info with fields", func(t *testing.T) {
+		var capture string
+		oldInfo := info
+		defer func() { info = oldInfo }()
+		info = func(format interface{}, args ...interface{}) {
+			if f, ok := format.(func() string); ok {
+				capture = f()
+			} else {
+				t.Error("First parameter passed to info is not a closure")
+			}
+		}
+
+		NewLogger().Info("Some log message", String("component", "api"), Int("attempt", 2))
+
+		var deserialized map[string]interface{}
+		json.Unmarshal([]byte(capture), &deserialized)
+
+		if deserialized["component"] != "api" {
+			t.Errorf("component = %v, want %v", deserialized["component"], "api")
+		}
+		if deserialized["attempt"] != float64(2) {
+			t.Errorf("attempt = %v, want %v", deserialized["attempt"], 2)
+		}
+		if deserialized["Message"] != "Some log message" {
+			t.Errorf("Message = %v, want %v", deserialized["Message"], "Some log message")
+		}
+	})
+}
+
+// ensures that With returns a child Logger that carries accumulated fields on every call
+func TestLoggerWith(t *testing.T) {
+	t.Run("With accumulates fields", func(t *testing.T) {
+		var capture string
+		oldDebug := debug
+		defer func() { debug = oldDebug }()
+		debug = func(format interface{}, args ...interface{}) {
+			if f, ok := format.(func() string); ok {
+				capture = f()
+			} else {
+				t.Error("First parameter passed to debug is not a closure")
+			}
+		}
+
+		child := NewLogger().With("component", "api")
+		child.Debug("Some log message")
+
+		var deserialized map[string]interface{}
+		json.Unmarshal([]byte(capture), &deserialized)
+
+		if deserialized["component"] != "api" {
+			t.Errorf("component = %v, want %v", deserialized["component"], "api")
+		}
+	})
+}
+
+// ensures that an odd number of keysAndValues pairs the trailing key with a MISSING sentinel
+func TestToFieldsOddArgs(t *testing.T) {
+	fields := toFields([]interface{}{"key1", "value1", "key2"})
+
+	if len(fields) != 2 {
+		t.Fatalf("len(fields) = %v, want %v", len(fields), 2)
+	}
+	if fields[1].Key != "key2" || fields[1].Value != "MISSING" {
+		t.Errorf("fields[1] = %+v, want {key2 MISSING}", fields[1])
+	}
+}
+
+// ensures that Error attaches the cause's message under the conventional "error" key
+func TestLoggerError(t *testing.T) {
+	t.Run("Error attaches cause", func(t *testing.T) {
+		var capture string
+		oldErr := err
+		defer func() { err = oldErr }()
+		err = func(format interface{}, args ...interface{}) error {
+			if f, ok := format.(func() string); ok {
+				capture = f()
+			} else {
+				t.Error("First parameter passed to err is not a closure")
+			}
+			return nil
+		}
+
+		NewLogger().Error(errors.New("boom"), "Something went wrong")
+
+		var deserialized map[string]interface{}
+		json.Unmarshal([]byte(capture), &deserialized)
+
+		if deserialized["error"] != "boom" {
+			t.Errorf("error = %v, want %v", deserialized["error"], "boom")
+		}
+	})
+}
+
+// ensures that RegisterSink attaches a sink that receives dispatched records, and that RemoveSink
+// detaches it again
+func TestRegisterRemoveSink(t *testing.T) {
+	t.Run("sink lifecycle", func(t *testing.T) {
+		var captured []string
+		sink := sinkFunc(func(level Level, produce func() string) {
+			captured = append(captured, produce())
+		})
+
+		RegisterSink("test-sink", sink)
+		defer RemoveSink("test-sink")
+
+		dispatch(LevelInfo, func() string { return "hello" })
+		if len(captured) != 1 || captured[0] != "hello" {
+			t.Errorf("captured = %v, want [hello]", captured)
+		}
+
+		RemoveSink("test-sink")
+		dispatch(LevelInfo, func() string { return "world" })
+		if len(captured) != 1 {
+			t.Errorf("captured = %v, want dispatch after RemoveSink to be a no-op", captured)
+		}
+	})
+}
+
+// ensures that SetLevel filters out records below the configured min-level, and that a
+// filtered-out record never invokes produce
+func TestSetLevelFiltersSink(t *testing.T) {
+	t.Run("min-level filter", func(t *testing.T) {
+		var produced int
+		sink := sinkFunc(func(level Level, produce func() string) {
+			produced++
+			produce()
+		})
+
+		RegisterSink("test-sink", sink)
+		defer RemoveSink("test-sink")
+		SetLevel("test-sink", LevelError)
+
+		invoked := false
+		dispatch(LevelDebug, func() string { invoked = true; return "" })
+
+		if produced != 0 {
+			t.Errorf("produced = %v, want 0", produced)
+		}
+		if invoked {
+			t.Error("produce was invoked for a record filtered out by SetLevel")
+		}
+
+		dispatch(LevelError, func() string { invoked = true; return "" })
+		if produced != 1 {
+			t.Errorf("produced = %v, want 1", produced)
+		}
+		if !invoked {
+			t.Error("produce was not invoked for a record admitted by SetLevel")
+		}
+	})
+}
+
+// sinkFunc adapts a plain function to the Sink interface for use in tests.
+type sinkFunc func(level Level, produce func() string)
+
+func (f sinkFunc) Log(level Level, produce func() string) {
+	f(level, produce)
+}
+
+// ensures that WithFields-based constructors beyond user-id/request-id are serialized correctly
+func TestSerializeContextAdditionalFields(t *testing.T) {
+	t.Run("trace and span ids", func(t *testing.T) {
+		ctx := context.Background()
+		ctx = WithTraceID(ctx, "trace-123")
+		ctx = WithSpanID(ctx, "span-456")
+
+		serialized := serializeContext(ctx)
+
+		if serialized["trace-id"] != "trace-123" {
+			t.Errorf("trace-id = %v, want %v", serialized["trace-id"], "trace-123")
+		}
+		if serialized["span-id"] != "span-456" {
+			t.Errorf("span-id = %v, want %v", serialized["span-id"], "span-456")
+		}
+	})
+}
+
+// ensures that a field set on a child context overrides the same key inherited from the parent
+func TestWithFieldsChildOverridesParent(t *testing.T) {
+	t.Run("duplicate key override", func(t *testing.T) {
+		parent := WithUserID(context.Background(), "parent-user")
+		child := WithUserID(parent, "child-user")
+
+		serialized := serializeContext(child)
+		if serialized["user-id"] != "child-user" {
+			t.Errorf("user-id = %v, want %v", serialized["user-id"], "child-user")
+		}
+
+		// the parent context itself must be unaffected by the child's override
+		parentSerialized := serializeContext(parent)
+		if parentSerialized["user-id"] != "parent-user" {
+			t.Errorf("parent user-id = %v, want %v", parentSerialized["user-id"], "parent-user")
+		}
+	})
+}
+
+// ensures that a BurstSampler lets the first N records through per (level, key) and then only
+// every Mth record after that
+func TestBurstSampler(t *testing.T) {
+	t.Run("first then every Nth", func(t *testing.T) {
+		s := NewBurstSampler(2, 3, time.Minute)
+
+		var got []bool
+		for i := 0; i < 8; i++ {
+			got = append(got, s.Allow(LevelInfo, "file.go:1"))
+		}
+
+		want := []bool{true, true, false, false, true, false, false, true}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("call %d = %v, want %v (full sequence %v)", i, got[i], want[i], got)
+				break
+			}
+		}
+	})
+}
+
+// ensures that sampled-out records never reach a registered sink, and that the message-producing
+// closure is never invoked
+func TestSampleSkipsClosureWhenDropped(t *testing.T) {
+	t.Run("dropped closure not invoked", func(t *testing.T) {
+		SetSampler(alwaysDropSampler{})
+		defer SetSampler(nil)
+
+		var sinkCalls int
+		sink := sinkFunc(func(level Level, produce func() string) { sinkCalls++; produce() })
+		RegisterSink("test-sink", sink)
+		defer RemoveSink("test-sink")
+
+		Debugc(context.Background(), "unused")
+
+		invoked := false
+		debug(func() string { invoked = true; return "" })
+
+		if sinkCalls != 0 {
+			t.Errorf("sinkCalls = %v, want 0", sinkCalls)
+		}
+		if invoked {
+			t.Error("produce was invoked for a record dropped by the sampler")
+		}
+	})
+}
+
+type alwaysDropSampler struct{}
+
+func (alwaysDropSampler) Allow(level Level, key string) bool { return false }
+
+// ensures that records submitted while async logging is enabled still reach sinks, and that
+// Shutdown drains the queue before returning
+func TestAsyncFlushAndShutdown(t *testing.T) {
+	t.Run("async pipeline drains on shutdown", func(t *testing.T) {
+		var mu sync.Mutex
+		var captured []string
+		sink := sinkFunc(func(level Level, produce func() string) {
+			mu.Lock()
+			captured = append(captured, produce())
+			mu.Unlock()
+		})
+		RegisterSink("test-sink", sink)
+		defer RemoveSink("test-sink")
+
+		EnableAsync(16, DropNewest)
+		defer DisableAsync()
+
+		for i := 0; i < 5; i++ {
+			submit(LevelInfo, func() string { return "queued" })
+		}
+
+		if err := Shutdown(context.Background()); err != nil {
+			t.Fatalf("Shutdown returned %v", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if len(captured) != 5 {
+			t.Errorf("captured = %v records, want 5", len(captured))
+		}
+	})
+}
+
+// ensures that DropNewest overflow discards records once the buffer is full rather than
+// blocking the caller, and that OnDrop observes the discard
+func TestAsyncDropNewestOverflow(t *testing.T) {
+	t.Run("drop newest on overflow", func(t *testing.T) {
+		EnableAsync(1, DropNewest)
+		defer DisableAsync()
+
+		var dropped uint64
+		OnDrop(func(count uint64) { atomic.StoreUint64(&dropped, count) })
+
+		// block the single background worker on a slow record before the queue fills up
+		block := make(chan struct{})
+		submit(LevelInfo, func() string { <-block; return "first" })
+		submit(LevelInfo, func() string { return "second" })
+		submit(LevelInfo, func() string { return "third" })
+		close(block)
+
+		if err := Flush(context.Background()); err != nil {
+			t.Fatalf("Flush returned %v", err)
+		}
+
+		if atomic.LoadUint64(&dropped) == 0 {
+			t.Error("OnDrop was never invoked despite submitting more records than the buffer holds")
+		}
+	})
+}
+
+// ensures that ApplyConfig closes a previous FileSink's underlying file once it's replaced,
+// rather than leaking the file descriptor on every reload
+func TestApplyConfigClosesReplacedFileSink(t *testing.T) {
+	t.Run("file sink closed on reload", func(t *testing.T) {
+		dir := t.TempDir()
+		path := dir + "/log.txt"
+
+		ApplyConfig(LogSettings{EnableFile: true, FilePath: path, FileLevel: LevelDebug})
+		defer ApplyConfig(LogSettings{})
+
+		sinksMu.RLock()
+		reg, ok := sinks["file"]
+		sinksMu.RUnlock()
+		if !ok {
+			t.Fatal("ApplyConfig did not register a file sink")
+		}
+		fileSink, ok := reg.sink.(*FileSink)
+		if !ok {
+			t.Fatalf("registered sink is %T, want *FileSink", reg.sink)
+		}
+
+		// reload with file logging disabled; the previous FileSink should be closed rather
+		// than left open with no reference to it
+		ApplyConfig(LogSettings{})
+
+		if err := fileSink.file.Close(); err == nil {
+			t.Error("ApplyConfig left the replaced FileSink's file open")
+		}
+	})
+}
+
+// ensures that DropOldest evictions are counted by OnDrop, not just the secondary race-failure
+// path — the eviction on line "<-a.queue" is the normal, expected way DropOldest discards a
+// record and must be tallied every time it happens
+func TestAsyncDropOldestCountsEviction(t *testing.T) {
+	t.Run("drop oldest on overflow", func(t *testing.T) {
+		EnableAsync(1, DropOldest)
+		defer DisableAsync()
+
+		var dropped uint64
+		OnDrop(func(count uint64) { atomic.StoreUint64(&dropped, count) })
+
+		// block the single background worker on a slow record before the queue fills up
+		block := make(chan struct{})
+		submit(LevelInfo, func() string { <-block; return "first" })
+		submit(LevelInfo, func() string { return "second" })
+		submit(LevelInfo, func() string { return "third" })
+		submit(LevelInfo, func() string { return "fourth" })
+		close(block)
+
+		if err := Flush(context.Background()); err != nil {
+			t.Fatalf("Flush returned %v", err)
+		}
+
+		if got := atomic.LoadUint64(&dropped); got < 2 {
+			t.Errorf("dropped = %v, want at least 2 evictions counted", got)
+		}
+	})
+}
@@ -0,0 +1,187 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// errDropped is returned by the default err sink when a record is suppressed by the active
+// Sampler. Nothing currently inspects it; it exists so err can still satisfy its signature
+// without invoking the (possibly expensive) format closure.
+var errDropped = errors.New("logger: message dropped by sampler")
+
+// Sampler decides whether a record for level, originating from the call site identified by key
+// ("file:line"), should be let through to the sink registry.
+type Sampler interface {
+	Allow(level Level, key string) bool
+}
+
+var (
+	samplerMu sync.RWMutex
+	sampler   Sampler
+)
+
+// SetSampler installs s as the active sampler. Passing nil (the default) disables sampling, so
+// every record is allowed through.
+func SetSampler(s Sampler) {
+	samplerMu.Lock()
+	defer samplerMu.Unlock()
+	sampler = s
+}
+
+// sample reports whether a record should proceed to the sink registry, recording a drop if not.
+func sample(level Level, key string) bool {
+	samplerMu.RLock()
+	s := sampler
+	samplerMu.RUnlock()
+
+	if s == nil {
+		return true
+	}
+	if s.Allow(level, key) {
+		return true
+	}
+	recordDrop(key)
+	return false
+}
+
+var (
+	dropCountsMu sync.Mutex
+	dropCounts   = map[string]uint64{}
+	reportOnce   sync.Once
+)
+
+// recordDrop tallies a suppressed record against key and, on the first drop of the process,
+// starts the background reporter that periodically flushes the tallies as a log record.
+func recordDrop(key string) {
+	dropCountsMu.Lock()
+	dropCounts[key]++
+	dropCountsMu.Unlock()
+	reportOnce.Do(startDropReporter)
+}
+
+func startDropReporter() {
+	go func() {
+		ticker := time.NewTicker(10 * time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			reportDrops()
+		}
+	}()
+}
+
+// reportDrops emits one synthetic log record per call site with a nonzero drop count since the
+// last report, then resets the tallies.
+func reportDrops() {
+	dropCountsMu.Lock()
+	snapshot := dropCounts
+	dropCounts = map[string]uint64{}
+	dropCountsMu.Unlock()
+
+	for key, count := range snapshot {
+		key, count := key, count
+		submit(LevelInfo, func() string {
+			return serializeLogMessage(context.Background(), "dropped messages",
+				Field{Key: "logger", Value: "sampler"},
+				Field{Key: "dropped", Value: count},
+				Field{Key: "key", Value: key},
+			)
+		})
+	}
+}
+
+// burstSampler allows the first N records per interval through for a given (level, key), then
+// only every Mth one after that, resetting the window once interval has elapsed.
+type burstSampler struct {
+	first      int
+	thereafter int
+	interval   time.Duration
+
+	mu      sync.Mutex
+	windows map[string]*burstWindow
+}
+
+type burstWindow struct {
+	start time.Time
+	count int
+}
+
+// NewBurstSampler returns a Sampler that allows the first N records per (level, key) per
+// interval through, then every thereafter-th record after that.
+func NewBurstSampler(first int, thereafter int, interval time.Duration) Sampler {
+	return &burstSampler{first: first, thereafter: thereafter, interval: interval, windows: map[string]*burstWindow{}}
+}
+
+func (s *burstSampler) Allow(level Level, key string) bool {
+	fullKey := sampleKey(level, key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	w, ok := s.windows[fullKey]
+	if !ok || now.Sub(w.start) > s.interval {
+		w = &burstWindow{start: now}
+		s.windows[fullKey] = w
+	}
+	w.count++
+
+	if w.count <= s.first {
+		return true
+	}
+	if s.thereafter <= 0 {
+		return false
+	}
+	return (w.count-s.first)%s.thereafter == 0
+}
+
+// rateLimitSampler enforces a token-bucket limit of perSecond records per (level, key).
+type rateLimitSampler struct {
+	perSecond float64
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+// NewRateLimitSampler returns a Sampler that allows at most perSecond records per (level, key)
+// through, per second, via a token bucket.
+func NewRateLimitSampler(perSecond int) Sampler {
+	return &rateLimitSampler{perSecond: float64(perSecond), buckets: map[string]*tokenBucket{}}
+}
+
+func (s *rateLimitSampler) Allow(level Level, key string) bool {
+	fullKey := sampleKey(level, key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	b, ok := s.buckets[fullKey]
+	if !ok {
+		b = &tokenBucket{tokens: s.perSecond, lastRefill: now}
+		s.buckets[fullKey] = b
+	}
+
+	b.tokens += now.Sub(b.lastRefill).Seconds() * s.perSecond
+	if b.tokens > s.perSecond {
+		b.tokens = s.perSecond
+	}
+	b.lastRefill = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func sampleKey(level Level, key string) string {
+	return key + string(rune('0'+level))
+}
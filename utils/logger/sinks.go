@@ -0,0 +1,186 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a single log record.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelError
+)
+
+// Sink is a log output backend. Log is only invoked for sinks whose min-level (set via SetLevel)
+// is at or below level; produce must be called to obtain the rendered message, so a sink that
+// decides not to record a record can skip calling it entirely.
+type Sink interface {
+	Log(level Level, produce func() string)
+}
+
+// sinkRegistration pairs a registered Sink with the min-level filter applied to it.
+type sinkRegistration struct {
+	sink     Sink
+	minLevel Level
+}
+
+// sinks starts out empty: nothing is recorded until a caller registers a sink via RegisterSink
+// or ApplyConfig, rather than a perpetual default sitting outside that lifecycle and receiving
+// every record regardless of what the caller has configured.
+var (
+	sinksMu sync.RWMutex
+	sinks   = map[string]*sinkRegistration{}
+)
+
+// RegisterSink attaches s to the active sink set under name, replacing any sink already
+// registered under that name. The sink defaults to recording every level until SetLevel is
+// called for name.
+func RegisterSink(name string, s Sink) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	sinks[name] = &sinkRegistration{sink: s, minLevel: LevelDebug}
+}
+
+// RemoveSink detaches the sink registered under name, if any.
+func RemoveSink(name string) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	delete(sinks, name)
+}
+
+// SetLevel sets the min-level filter for the sink registered under name. Records below lvl are
+// never dispatched to that sink. SetLevel is a no-op if no sink is registered under name.
+func SetLevel(name string, lvl Level) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	if reg, ok := sinks[name]; ok {
+		reg.minLevel = lvl
+	}
+}
+
+// dispatch fans a record out to every registered sink whose min-level admits it. produce is
+// handed to each admitting sink rather than being evaluated here, so it still costs nothing to
+// call dispatch when every sink filters the record out.
+func dispatch(level Level, produce func() string) {
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+	for _, reg := range sinks {
+		if level >= reg.minLevel {
+			reg.sink.Log(level, produce)
+		}
+	}
+}
+
+// StdoutSink writes every record it receives to stdout.
+type StdoutSink struct{}
+
+// NewStdoutSink returns a Sink that writes to stdout.
+func NewStdoutSink() *StdoutSink {
+	return &StdoutSink{}
+}
+
+// Log implements Sink.
+func (s *StdoutSink) Log(level Level, produce func() string) {
+	fmt.Println(produce())
+}
+
+// FileSink appends every record it receives to a single open file.
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink opens path for appending (creating it if necessary) and returns a Sink backed by
+// it. The caller is responsible for calling Close when the sink is no longer needed.
+func NewFileSink(path string) (*FileSink, error) {
+	file, fileErr := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if fileErr != nil {
+		return nil, fileErr
+	}
+	return &FileSink{file: file}, nil
+}
+
+// Log implements Sink.
+func (s *FileSink) Log(level Level, produce func() string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprintln(s.file, produce())
+}
+
+// Close closes the underlying file.
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+// LogSettings describes the sinks that should be active and the severity each should record at.
+// It's expected to be part of the application's broader config struct; calling ApplyConfig
+// whenever it changes (e.g. from a config.AddConfigListener callback) re-derives the active sink
+// set without requiring a restart.
+type LogSettings struct {
+	EnableConsole bool
+	ConsoleLevel  Level
+
+	EnableFile bool
+	FileLevel  Level
+	FilePath   string
+
+	EnableSyslog bool
+	SyslogLevel  Level
+	SyslogTag    string
+
+	// SamplingMode selects which Sampler, if any, guards the sinks above: "burst", "rate", or
+	// "" to disable sampling entirely.
+	SamplingMode          string
+	SampleBurstFirst      int
+	SampleBurstThereafter int
+	SampleBurstInterval   time.Duration
+	SampleRatePerSecond   int
+}
+
+// ApplyConfig replaces the active sink set with the one described by settings. Sinks that fail
+// to open (e.g. an unwritable file path) are dropped rather than leaving the previous sink set
+// partially in place. Outgoing sinks that hold a resource (e.g. a FileSink's open file) are
+// closed once they're no longer reachable, so a reload doesn't leak a file descriptor per call.
+func ApplyConfig(settings LogSettings) {
+	next := map[string]*sinkRegistration{}
+
+	if settings.EnableConsole {
+		next["console"] = &sinkRegistration{sink: NewStdoutSink(), minLevel: settings.ConsoleLevel}
+	}
+	if settings.EnableFile && settings.FilePath != "" {
+		if fileSink, fileErr := NewFileSink(settings.FilePath); fileErr == nil {
+			next["file"] = &sinkRegistration{sink: fileSink, minLevel: settings.FileLevel}
+		}
+	}
+	if settings.EnableSyslog {
+		if syslogSink, syslogErr := NewSyslogSink(settings.SyslogTag); syslogErr == nil {
+			next["syslog"] = &sinkRegistration{sink: syslogSink, minLevel: settings.SyslogLevel}
+		}
+	}
+
+	sinksMu.Lock()
+	previous := sinks
+	sinks = next
+	sinksMu.Unlock()
+
+	for _, reg := range previous {
+		if closer, ok := reg.sink.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+
+	switch settings.SamplingMode {
+	case "burst":
+		SetSampler(NewBurstSampler(settings.SampleBurstFirst, settings.SampleBurstThereafter, settings.SampleBurstInterval))
+	case "rate":
+		SetSampler(NewRateLimitSampler(settings.SampleRatePerSecond))
+	default:
+		SetSampler(nil)
+	}
+}
@@ -0,0 +1,37 @@
+// +build !windows
+
+package logger
+
+import "log/syslog"
+
+// SyslogSink writes records to the local syslog daemon.
+type SyslogSink struct {
+	writer *syslog.Writer
+}
+
+// NewSyslogSink opens a connection to the local syslog daemon, tagging every record with tag.
+func NewSyslogSink(tag string) (*SyslogSink, error) {
+	writer, err := syslog.New(syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &SyslogSink{writer: writer}, nil
+}
+
+// Log implements Sink.
+func (s *SyslogSink) Log(level Level, produce func() string) {
+	message := produce()
+	switch level {
+	case LevelError:
+		s.writer.Err(message)
+	case LevelInfo:
+		s.writer.Info(message)
+	default:
+		s.writer.Debug(message)
+	}
+}
+
+// Close closes the underlying syslog connection.
+func (s *SyslogSink) Close() error {
+	return s.writer.Close()
+}
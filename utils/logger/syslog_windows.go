@@ -0,0 +1,21 @@
+// +build windows
+
+package logger
+
+import "errors"
+
+// SyslogSink is unavailable on Windows; the stdlib log/syslog package doesn't support it.
+type SyslogSink struct{}
+
+// NewSyslogSink always fails on Windows.
+func NewSyslogSink(tag string) (*SyslogSink, error) {
+	return nil, errors.New("logger: syslog sink is not supported on windows")
+}
+
+// Log implements Sink.
+func (s *SyslogSink) Log(level Level, produce func() string) {}
+
+// Close is a no-op; SyslogSink never holds an open connection on Windows.
+func (s *SyslogSink) Close() error {
+	return nil
+}